@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// artefactByObjectNumber looks up a single artefact by its ObjectNumber.
+func artefactByObjectNumber(results []ArtefactDTO, objectNumber string) (ArtefactDTO, bool) {
+	for _, a := range results {
+		if a.ObjectNumber == objectNumber {
+			return a, true
+		}
+	}
+	return ArtefactDTO{}, false
+}
+
+// loadArtefacts reads and parses qm_data.json, the same way the /artefacts
+// handler does.
+func loadArtefacts() ([]ArtefactDTO, error) {
+	filePath, err := findFile("qm_data.json")
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var qmResponse QMResponse
+	if err := json.Unmarshal(data, &qmResponse); err != nil {
+		return nil, err
+	}
+	return qmResponse.Results, nil
+}
+
+// schemaOrgArtefact is the schema.org VisualArtwork/CreativeWork
+// representation of an ArtefactDTO, used for Accept: application/ld+json.
+type schemaOrgArtefact struct {
+	Context         string   `json:"@context"`
+	Type            string   `json:"@type"`
+	Name            string   `json:"name"`
+	Creator         string   `json:"creator,omitempty"`
+	Image           string   `json:"image,omitempty"`
+	LocationCreated string   `json:"locationCreated,omitempty"`
+	InLanguage      []string `json:"inLanguage"`
+}
+
+func toSchemaOrg(a ArtefactDTO) schemaOrgArtefact {
+	image := ""
+	if len(a.ObjectImages.Original) > 0 {
+		image = a.ObjectImages.Original[0].URL
+	} else if len(a.ObjectImages.Card) > 0 {
+		image = a.ObjectImages.Card[0].URL
+	}
+
+	return schemaOrgArtefact{
+		Context:         "https://schema.org",
+		Type:            "VisualArtwork",
+		Name:            a.TitleEN,
+		Creator:         a.ArtistEN,
+		Image:           image,
+		LocationCreated: a.Museum.LabelEN,
+		InLanguage:      []string{"en", "ar"},
+	}
+}
+
+// dublinCoreRecord maps an ArtefactDTO onto an OAI Dublin Core record, with
+// both language variants emitted via xml:lang.
+type dublinCoreRecord struct {
+	XMLName     xml.Name       `xml:"oai_dc:dc"`
+	Xmlns       string         `xml:"xmlns:oai_dc,attr"`
+	XmlnsDC     string         `xml:"xmlns:dc,attr"`
+	Title       []dcLangString `xml:"dc:title"`
+	Creator     []dcLangString `xml:"dc:creator"`
+	Type        []dcLangString `xml:"dc:type"`
+	Description []dcLangString `xml:"dc:description"`
+	Publisher   dcLangString   `xml:"dc:publisher"`
+	Identifier  string         `xml:"dc:identifier"`
+}
+
+type dcLangString struct {
+	Lang  string `xml:"xml:lang,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+func toDublinCore(a ArtefactDTO) dublinCoreRecord {
+	return dublinCoreRecord{
+		Xmlns:   "http://www.openarchives.org/OAI/2.0/oai_dc/",
+		XmlnsDC: "http://purl.org/dc/elements/1.1/",
+		Title: []dcLangString{
+			{Lang: "en", Value: a.TitleEN},
+			{Lang: "ar", Value: a.TitleAR},
+		},
+		Creator: []dcLangString{
+			{Lang: "en", Value: a.ArtistEN},
+			{Lang: "ar", Value: a.ArtistAR},
+		},
+		Type: []dcLangString{
+			{Lang: "en", Value: a.ObjectNameEN},
+			{Lang: "ar", Value: a.ObjectNameAR},
+		},
+		Description: []dcLangString{
+			{Lang: "en", Value: a.SummaryEN},
+			{Lang: "ar", Value: a.SummaryAR},
+		},
+		Publisher:  dcLangString{Value: a.Museum.Label},
+		Identifier: a.ObjectNumber,
+	}
+}
+
+// negotiatedFormat reports which representation writeNegotiated will pick
+// for the given request's Accept header, so callers can fold it into cache
+// keys (e.g. ETag) alongside emitting Vary: Accept.
+func negotiatedFormat(r *http.Request) string {
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "application/ld+json"):
+		return "ld+json"
+	case strings.Contains(accept, "application/xml"):
+		return "dc+xml"
+	default:
+		return "json"
+	}
+}
+
+// writeNegotiated writes items in the format requested by the Accept header,
+// falling back to fn (typically writeJSON with the plain DTOs) when neither
+// JSON-LD nor Dublin Core XML is requested. The response always varies by
+// Accept, since the three representations are not interchangeable for
+// caching purposes.
+func writeNegotiated(w http.ResponseWriter, r *http.Request, artefacts []ArtefactDTO, fn func()) {
+	w.Header().Set("Vary", "Accept")
+
+	switch negotiatedFormat(r) {
+	case "ld+json":
+		nodes := make([]schemaOrgArtefact, 0, len(artefacts))
+		for _, a := range artefacts {
+			nodes = append(nodes, toSchemaOrg(a))
+		}
+		w.Header().Set("Content-Type", "application/ld+json")
+		if len(nodes) == 1 {
+			if err := json.NewEncoder(w).Encode(nodes[0]); err != nil {
+				log.Printf("Error encoding JSON-LD response: %v", err)
+			}
+			return
+		}
+		if err := json.NewEncoder(w).Encode(struct {
+			Context string              `json:"@context"`
+			Graph   []schemaOrgArtefact `json:"@graph"`
+		}{Context: "https://schema.org", Graph: nodes}); err != nil {
+			log.Printf("Error encoding JSON-LD response: %v", err)
+		}
+
+	case "dc+xml":
+		records := make([]dublinCoreRecord, 0, len(artefacts))
+		for _, a := range artefacts {
+			records = append(records, toDublinCore(a))
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(xml.Header))
+		var err error
+		if len(records) == 1 {
+			err = xml.NewEncoder(w).Encode(records[0])
+		} else {
+			err = xml.NewEncoder(w).Encode(struct {
+				XMLName xml.Name           `xml:"oai_dc:dcCollection"`
+				Records []dublinCoreRecord `xml:"record"`
+			}{Records: records})
+		}
+		if err != nil {
+			log.Printf("Error encoding Dublin Core response: %v", err)
+		}
+
+	default:
+		fn()
+	}
+}
+
+func artefactDetailHandler(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Received request: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+
+	segments := splitAndTrim(strings.TrimPrefix(r.URL.Path, "/artefacts/"), "/")
+	if len(segments) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	objectNumber := segments[0]
+
+	artefacts, err := loadArtefacts()
+	if err != nil {
+		http.Error(w, "Error loading qm_data.json: "+err.Error(), http.StatusInternalServerError)
+		log.Printf("Error loading artefacts: %v", err)
+		return
+	}
+
+	artefact, ok := artefactByObjectNumber(artefacts, objectNumber)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if len(segments) > 1 {
+		switch segments[1] {
+		case "status":
+			artefactStatusHandler(w, r, artefact)
+		case "image":
+			artefactImageHandler(w, r, artefact)
+		case "related":
+			artefactRelatedHandler(w, r, artefact.ObjectNumber)
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	writeNegotiated(w, r, []ArtefactDTO{artefact}, func() {
+		writeJSON(w, artefact)
+	})
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+)
+
+const earthRadiusKm = 6371.0
+
+// defaultRadiusKm is used when the caller omits radius_km on a /nearby request.
+const defaultRadiusKm = 2.0
+
+// haversineKm returns the great-circle distance in kilometres between two
+// coordinates using the haversine formula.
+func haversineKm(a, b Coordinates) float64 {
+	phi1 := a.Latitude * math.Pi / 180
+	phi2 := b.Latitude * math.Pi / 180
+	deltaPhi := (b.Latitude - a.Latitude) * math.Pi / 180
+	deltaLambda := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	sinDeltaPhi := math.Sin(deltaPhi / 2)
+	sinDeltaLambda := math.Sin(deltaLambda / 2)
+
+	h := sinDeltaPhi*sinDeltaPhi + math.Cos(phi1)*math.Cos(phi2)*sinDeltaLambda*sinDeltaLambda
+	c := 2 * math.Atan2(math.Sqrt(h), math.Sqrt(1-h))
+	return earthRadiusKm * c
+}
+
+// boundingBox is an optional rectangular prefilter used to avoid computing
+// haversine distance for every record in large datasets.
+type boundingBox struct {
+	minLat, minLon, maxLat, maxLon float64
+}
+
+func parseBoundingBox(r *http.Request) (boundingBox, bool) {
+	raw := r.URL.Query().Get("bbox")
+	if raw == "" {
+		return boundingBox{}, false
+	}
+	parts := splitAndTrim(raw, ",")
+	if len(parts) != 4 {
+		return boundingBox{}, false
+	}
+	vals := make([]float64, 4)
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return boundingBox{}, false
+		}
+		vals[i] = v
+	}
+	return boundingBox{minLat: vals[0], minLon: vals[1], maxLat: vals[2], maxLon: vals[3]}, true
+}
+
+func (bb boundingBox) contains(c Coordinates) bool {
+	return c.Latitude >= bb.minLat && c.Latitude <= bb.maxLat &&
+		c.Longitude >= bb.minLon && c.Longitude <= bb.maxLon
+}
+
+func isZeroCoords(c Coordinates) bool {
+	return c.Latitude == 0 && c.Longitude == 0
+}
+
+// nearbyParams holds the parsed common query parameters shared by the
+// /exhibits/nearby and /artefacts/nearby endpoints.
+type nearbyParams struct {
+	origin   Coordinates
+	radiusKm float64
+	limit    int
+	hasLimit bool
+	bbox     boundingBox
+	hasBBox  bool
+}
+
+func parseNearbyParams(r *http.Request) (nearbyParams, error) {
+	q := r.URL.Query()
+
+	lat, err := strconv.ParseFloat(q.Get("lat"), 64)
+	if err != nil {
+		return nearbyParams{}, err
+	}
+	lon, err := strconv.ParseFloat(q.Get("lon"), 64)
+	if err != nil {
+		return nearbyParams{}, err
+	}
+
+	radiusKm := defaultRadiusKm
+	if raw := q.Get("radius_km"); raw != "" {
+		if v, err := strconv.ParseFloat(raw, 64); err == nil {
+			radiusKm = v
+		}
+	}
+
+	params := nearbyParams{
+		origin:   Coordinates{Latitude: lat, Longitude: lon},
+		radiusKm: radiusKm,
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v > 0 {
+			params.limit = v
+			params.hasLimit = true
+		}
+	}
+
+	if bbox, ok := parseBoundingBox(r); ok {
+		params.bbox = bbox
+		params.hasBBox = true
+	}
+
+	return params, nil
+}
+
+type exhibitWithDistance struct {
+	ExhibitDTO
+	DistanceKm float64 `json:"distance_km"`
+}
+
+type artefactWithDistance struct {
+	ArtefactDTO
+	DistanceKm float64 `json:"distance_km"`
+}
+
+func exhibitsNearbyHandler(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Received request: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+
+	params, err := parseNearbyParams(r)
+	if err != nil {
+		http.Error(w, "lat and lon are required and must be valid numbers", http.StatusBadRequest)
+		return
+	}
+
+	filePath, err := findFile("exhibits.json")
+	if err != nil {
+		http.Error(w, "Error finding exhibits.json", http.StatusInternalServerError)
+		log.Printf("Error finding file: %v", err)
+		return
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		http.Error(w, "Error reading exhibits.json", http.StatusInternalServerError)
+		log.Printf("Error reading file %s: %v", filePath, err)
+		return
+	}
+
+	var exhibits []ExhibitDTO
+	if err := json.Unmarshal(data, &exhibits); err != nil {
+		http.Error(w, "Error parsing exhibits.json", http.StatusInternalServerError)
+		log.Printf("Error unmarshalling json: %v", err)
+		return
+	}
+
+	var nearby []exhibitWithDistance
+	for _, exhibit := range exhibits {
+		if isZeroCoords(exhibit.Coords) {
+			continue
+		}
+		if params.hasBBox && !params.bbox.contains(exhibit.Coords) {
+			continue
+		}
+		d := haversineKm(params.origin, exhibit.Coords)
+		if d > params.radiusKm {
+			continue
+		}
+		nearby = append(nearby, exhibitWithDistance{ExhibitDTO: exhibit, DistanceKm: d})
+	}
+
+	sort.Slice(nearby, func(i, j int) bool { return nearby[i].DistanceKm < nearby[j].DistanceKm })
+
+	if params.hasLimit && len(nearby) > params.limit {
+		nearby = nearby[:params.limit]
+	}
+
+	writeJSON(w, nearby)
+}
+
+func artefactsNearbyHandler(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Received request: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+
+	params, err := parseNearbyParams(r)
+	if err != nil {
+		http.Error(w, "lat and lon are required and must be valid numbers", http.StatusBadRequest)
+		return
+	}
+
+	filePath, err := findFile("qm_data.json")
+	if err != nil {
+		http.Error(w, "Error finding qm_data.json: "+err.Error(), http.StatusInternalServerError)
+		log.Printf("Error finding file: %v", err)
+		return
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		http.Error(w, "Error reading qm_data.json: "+err.Error(), http.StatusInternalServerError)
+		log.Printf("Error reading file %s: %v", filePath, err)
+		return
+	}
+
+	var qmResponse QMResponse
+	if err := json.Unmarshal(data, &qmResponse); err != nil {
+		http.Error(w, "Error parsing qm_data.json: "+err.Error(), http.StatusInternalServerError)
+		log.Printf("Error unmarshalling json: %v", err)
+		return
+	}
+
+	var nearby []artefactWithDistance
+	for _, artefact := range qmResponse.Results {
+		if isZeroCoords(artefact.Coords) {
+			continue
+		}
+		if params.hasBBox && !params.bbox.contains(artefact.Coords) {
+			continue
+		}
+		d := haversineKm(params.origin, artefact.Coords)
+		if d > params.radiusKm {
+			continue
+		}
+		nearby = append(nearby, artefactWithDistance{ArtefactDTO: artefact, DistanceKm: d})
+	}
+
+	sort.Slice(nearby, func(i, j int) bool { return nearby[i].DistanceKm < nearby[j].DistanceKm })
+
+	if params.hasLimit && len(nearby) > params.limit {
+		nearby = nearby[:params.limit]
+	}
+
+	writeJSON(w, nearby)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultTimeZone = "Asia/Qatar"
+
+// openingInterval is a concrete, timezone-resolved [start, end) window during
+// which a museum is open, derived from a single OpeningTime entry on a given
+// calendar day.
+type openingInterval struct {
+	start, end           time.Time
+	openingAt, closingAt string
+}
+
+func parseHHMM(hhmm string, day time.Time, loc *time.Location) (time.Time, error) {
+	parts := strings.Split(hhmm, ":")
+	if len(parts) != 2 {
+		return time.Time{}, fmt.Errorf("invalid time %q", hhmm)
+	}
+	h, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return time.Time{}, err
+	}
+	m, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return time.Time{}, err
+	}
+	y, mo, d := day.Date()
+	return time.Date(y, mo, d, h, m, 0, 0, loc), nil
+}
+
+// openingIntervals expands the weekly OpeningTime schedule into concrete
+// intervals around `at`, carrying overnight windows (ClosingAt < OpeningAt)
+// across midnight. All-day closures (missing openingAt/closingAt) are
+// skipped, which leaves the museum closed on that weekday.
+func openingIntervals(times []OpeningTime, loc *time.Location, at time.Time) []openingInterval {
+	y, m, d := at.In(loc).Date()
+	startDay := time.Date(y, m, d, 0, 0, 0, 0, loc).AddDate(0, 0, -1)
+
+	var intervals []openingInterval
+	for offset := 0; offset < 9; offset++ {
+		day := startDay.AddDate(0, 0, offset)
+		weekday := int(day.Weekday())
+
+		for _, ot := range times {
+			if ot.OpeningAt == "" || ot.ClosingAt == "" || ot.Weekday.Number != weekday {
+				continue
+			}
+			start, err := parseHHMM(ot.OpeningAt, day, loc)
+			if err != nil {
+				continue
+			}
+			end, err := parseHHMM(ot.ClosingAt, day, loc)
+			if err != nil {
+				continue
+			}
+			if ot.ClosingAt <= ot.OpeningAt {
+				// Overnight range: closes the following calendar day.
+				end = end.AddDate(0, 0, 1)
+			}
+			intervals = append(intervals, openingInterval{start: start, end: end, openingAt: ot.OpeningAt, closingAt: ot.ClosingAt})
+		}
+	}
+	return intervals
+}
+
+// openStatusAt reports whether the museum is open at `at`, along with the
+// opening/closing strings for the relevant window and the instant of the
+// next open/closed transition.
+func openStatusAt(times []OpeningTime, loc *time.Location, at time.Time) (open bool, opensAt, closesAt string, nextChange time.Time) {
+	intervals := openingIntervals(times, loc, at)
+
+	for _, iv := range intervals {
+		if !at.Before(iv.start) && at.Before(iv.end) {
+			return true, iv.openingAt, iv.closingAt, iv.end
+		}
+	}
+
+	var next *openingInterval
+	for i := range intervals {
+		if intervals[i].start.After(at) && (next == nil || intervals[i].start.Before(next.start)) {
+			next = &intervals[i]
+		}
+	}
+	if next != nil {
+		return false, next.openingAt, next.closingAt, next.start
+	}
+	return false, "", "", time.Time{}
+}
+
+// resolveInstant parses the `tz` and `open_on` query params, defaulting to
+// the current time in Asia/Qatar.
+func resolveInstant(r *http.Request) (time.Time, *time.Location, error) {
+	q := r.URL.Query()
+
+	tzName := q.Get("tz")
+	if tzName == "" {
+		tzName = defaultTimeZone
+	}
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("invalid tz %q: %w", tzName, err)
+	}
+
+	if raw := q.Get("open_on"); raw != "" {
+		at, err := time.ParseInLocation("2006-01-02T15:04", raw, loc)
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf("invalid open_on %q: %w", raw, err)
+		}
+		return at, loc, nil
+	}
+
+	return time.Now().In(loc), loc, nil
+}
+
+// openFilter reports whether the request asked to filter artefacts by
+// opening status (open_now=true or open_on=...), and the instant to check.
+func openFilter(r *http.Request) (shouldFilter bool, at time.Time, loc *time.Location, err error) {
+	q := r.URL.Query()
+	if q.Get("open_now") != "true" && q.Get("open_on") == "" {
+		return false, time.Time{}, nil, nil
+	}
+	at, loc, err = resolveInstant(r)
+	return true, at, loc, err
+}
+
+type artefactStatus struct {
+	Open       bool   `json:"open"`
+	OpensAt    string `json:"opens_at"`
+	ClosesAt   string `json:"closes_at"`
+	NextChange string `json:"next_change"`
+}
+
+func artefactStatusHandler(w http.ResponseWriter, r *http.Request, artefact ArtefactDTO) {
+	at, loc, err := resolveInstant(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	open, opensAt, closesAt, nextChange := openStatusAt(artefact.OpeningTimes, loc, at)
+
+	nextChangeStr := ""
+	if !nextChange.IsZero() {
+		nextChangeStr = nextChange.Format(time.RFC3339)
+	}
+
+	writeJSON(w, artefactStatus{
+		Open:       open,
+		OpensAt:    opensAt,
+		ClosesAt:   closesAt,
+		NextChange: nextChangeStr,
+	})
+}
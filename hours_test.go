@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOpenStatusAt(t *testing.T) {
+	loc, err := time.LoadLocation(defaultTimeZone)
+	if err != nil {
+		t.Fatalf("failed to load %s: %v", defaultTimeZone, err)
+	}
+
+	weekdayTimes := []OpeningTime{
+		{OpeningAt: "09:00", ClosingAt: "20:00", Weekday: Weekday{Number: 1, Name: "Monday"}},
+	}
+	overnightTimes := []OpeningTime{
+		{OpeningAt: "22:00", ClosingAt: "02:00", Weekday: Weekday{Number: 5, Name: "Friday"}},
+	}
+
+	tests := []struct {
+		name     string
+		times    []OpeningTime
+		at       string // local time (2006-01-02T15:04:05) in defaultTimeZone
+		wantOpen bool
+	}{
+		{
+			name:     "open mid-afternoon",
+			times:    weekdayTimes,
+			at:       "2026-01-05T14:00:00", // a Monday
+			wantOpen: true,
+		},
+		{
+			name:     "closed before opening time",
+			times:    weekdayTimes,
+			at:       "2026-01-05T08:59:00",
+			wantOpen: false,
+		},
+		{
+			name:     "closed exactly at closing time",
+			times:    weekdayTimes,
+			at:       "2026-01-05T20:00:00",
+			wantOpen: false,
+		},
+		{
+			name:     "overnight range open just after opening",
+			times:    overnightTimes,
+			at:       "2026-01-02T23:00:00", // a Friday
+			wantOpen: true,
+		},
+		{
+			name:     "overnight range open just before midnight wrap closes",
+			times:    overnightTimes,
+			at:       "2026-01-03T01:30:00", // the following Saturday, before 02:00
+			wantOpen: true,
+		},
+		{
+			name:     "overnight range closed after wrap",
+			times:    overnightTimes,
+			at:       "2026-01-03T03:00:00",
+			wantOpen: false,
+		},
+		{
+			name:     "empty opening times is always closed",
+			times:    nil,
+			at:       "2026-01-05T14:00:00",
+			wantOpen: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := time.ParseInLocation("2006-01-02T15:04:05", tt.at, loc)
+			if err != nil {
+				t.Fatalf("failed to parse %q: %v", tt.at, err)
+			}
+
+			open, _, _, _ := openStatusAt(tt.times, loc, at)
+			if open != tt.wantOpen {
+				t.Errorf("openStatusAt() open = %v, want %v", open, tt.wantOpen)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+const maxImageCacheEntries = 500
+
+// imageCache is a small on-disk LRU cache for re-encoded artefact images,
+// keyed by {objectNumber, variant, w, h, fmt}.
+type imageCache struct {
+	mu      sync.Mutex
+	dir     string
+	order   []string
+	maxSize int
+}
+
+func newImageCache(dir string, maxSize int) *imageCache {
+	_ = os.MkdirAll(dir, 0o755)
+	return &imageCache{dir: dir, maxSize: maxSize}
+}
+
+func (c *imageCache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+func (c *imageCache) touch(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, k := range c.order {
+		if k == key {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	c.order = append(c.order, key)
+	for len(c.order) > c.maxSize {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		_ = os.Remove(c.path(oldest))
+	}
+}
+
+func (c *imageCache) get(key string) (string, bool) {
+	p := c.path(key)
+	if info, err := os.Stat(p); err == nil && !info.IsDir() {
+		c.touch(key)
+		return p, true
+	}
+	return "", false
+}
+
+func (c *imageCache) put(key string, data []byte) (string, error) {
+	p := c.path(key)
+	if err := os.WriteFile(p, data, 0o644); err != nil {
+		return "", err
+	}
+	c.touch(key)
+	return p, nil
+}
+
+var defaultImageCache = newImageCache(filepath.Join(os.TempDir(), "exhibits_stub_image_cache"), maxImageCacheEntries)
+
+// cropAroundFocal crops img to w x h, keeping FocalPoint inside the output
+// frame when possible, clamped to the image bounds. A zero FocalPoint falls
+// back to a center crop.
+func cropAroundFocal(img image.Image, w, h int, focal FocalPoint) image.Image {
+	bounds := img.Bounds()
+	imgW, imgH := bounds.Dx(), bounds.Dy()
+
+	cropW, cropH := w, h
+	if cropW > imgW {
+		cropW = imgW
+	}
+	if cropH > imgH {
+		cropH = imgH
+	}
+
+	fx, fy := focal.X, focal.Y
+	if fx == 0 && fy == 0 {
+		fx, fy = imgW/2, imgH/2
+	}
+
+	x0 := fx - cropW/2
+	y0 := fy - cropH/2
+	if x0 < 0 {
+		x0 = 0
+	}
+	if y0 < 0 {
+		y0 = 0
+	}
+	if x0+cropW > imgW {
+		x0 = imgW - cropW
+	}
+	if y0+cropH > imgH {
+		y0 = imgH - cropH
+	}
+
+	rect := image.Rect(bounds.Min.X+x0, bounds.Min.Y+y0, bounds.Min.X+x0+cropW, bounds.Min.Y+y0+cropH)
+
+	dst := image.NewRGBA(image.Rect(0, 0, cropW, cropH))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst
+}
+
+// resizeNearest scales img to exactly w x h using nearest-neighbor sampling.
+func resizeNearest(img image.Image, w, h int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() == w && bounds.Dy() == h {
+		return img
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		srcY := bounds.Min.Y + y*bounds.Dy()/h
+		for x := 0; x < w; x++ {
+			srcX := bounds.Min.X + x*bounds.Dx()/w
+			dst.Set(x, y, img.At(srcX, srcY))
+		}
+	}
+	return dst
+}
+
+func artefactImageHandler(w http.ResponseWriter, r *http.Request, artefact ArtefactDTO) {
+	q := r.URL.Query()
+
+	variant := q.Get("variant")
+	if variant != "original" {
+		variant = "card"
+	}
+
+	format := q.Get("fmt")
+	if format == "" {
+		format = "jpeg"
+	}
+	if format != "jpeg" && format != "webp" {
+		http.Error(w, "fmt must be jpeg or webp", http.StatusBadRequest)
+		return
+	}
+
+	width, _ := strconv.Atoi(q.Get("w"))
+	height, _ := strconv.Atoi(q.Get("h"))
+
+	images := artefact.ObjectImages.Card
+	if variant == "original" {
+		images = artefact.ObjectImages.Original
+	}
+	if len(images) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	src := images[0]
+
+	if width <= 0 {
+		width = src.Width
+	}
+	if height <= 0 {
+		height = src.Height
+	}
+	if width <= 0 || height <= 0 {
+		http.Error(w, "unable to determine target image dimensions", http.StatusBadRequest)
+		return
+	}
+
+	cacheKey := fmt.Sprintf("%s_%s_%dx%d.%s", artefact.ObjectNumber, variant, width, height, format)
+
+	if cachedPath, ok := defaultImageCache.get(cacheKey); ok {
+		serveCachedImage(w, r, cachedPath, src.URL, format)
+		return
+	}
+
+	if format == "webp" {
+		log.Printf("fmt=webp requested for %s: no webp encoder available, falling back to jpeg", artefact.ObjectNumber)
+	}
+
+	resp, err := http.Get(src.URL)
+	if err != nil {
+		http.Error(w, "Error fetching upstream image: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		http.Error(w, "Upstream image returned "+resp.Status, http.StatusBadGateway)
+		return
+	}
+
+	img, _, err := image.Decode(resp.Body)
+	if err != nil {
+		http.Error(w, "Error decoding upstream image: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	cropped := cropAroundFocal(img, width, height, src.FocalPoint)
+	resized := resizeNearest(cropped, width, height)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 85}); err != nil {
+		http.Error(w, "Error encoding image: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	cachedPath, err := defaultImageCache.put(cacheKey, buf.Bytes())
+	if err != nil {
+		log.Printf("Error writing image cache: %v", err)
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Content-Type", "image/jpeg")
+		if format == "webp" {
+			w.Header().Set("X-Image-Format-Fallback", "webp requested, served jpeg (no webp encoder available)")
+		}
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="canonical"`, src.URL))
+		_, _ = w.Write(buf.Bytes())
+		return
+	}
+
+	serveCachedImage(w, r, cachedPath, src.URL, format)
+}
+
+// serveCachedImage streams the cached file at path. The cached bytes are
+// always JPEG-encoded (the standard library has no webp encoder and this
+// stub vendors no codec for it), so Content-Type always reflects that
+// honestly; requests for fmt=webp are served as jpeg with an
+// X-Image-Format-Fallback header noting the substitution rather than being
+// mislabeled as image/webp.
+func serveCachedImage(w http.ResponseWriter, r *http.Request, path, canonicalURL, format string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		http.Error(w, "Error reading cached image", http.StatusInternalServerError)
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		http.Error(w, "Error reading cached image", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Content-Type", "image/jpeg")
+	if format == "webp" {
+		w.Header().Set("X-Image-Format-Fallback", "webp requested, served jpeg (no webp encoder available)")
+	}
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="canonical"`, canonicalURL))
+
+	http.ServeContent(w, r, filepath.Base(path), info.ModTime(), f)
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type LocalizedString struct {
@@ -139,6 +140,31 @@ func findFile(filename string) (string, error) {
 	return filename, os.ErrNotExist
 }
 
+// splitAndTrim splits s on sep and trims whitespace from each resulting part,
+// dropping empty parts.
+func splitAndTrim(s, sep string) []string {
+	var out []string
+	for _, part := range strings.Split(s, sep) {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// writeJSON sets the common CORS/JSON response headers and encodes v to w.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
 func main() {
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
@@ -154,6 +180,12 @@ func main() {
 			log.Printf("Error finding file: %v", err)
 			return
 		}
+		info, err := os.Stat(filePath)
+		if err != nil {
+			http.Error(w, "Error stating exhibits.json", http.StatusInternalServerError)
+			log.Printf("Error stating file %s: %v", filePath, err)
+			return
+		}
 		data, err := os.ReadFile(filePath)
 		if err != nil {
 			http.Error(w, "Error reading exhibits.json", http.StatusInternalServerError)
@@ -168,6 +200,20 @@ func main() {
 			return
 		}
 
+		page := parsePageParams(r)
+
+		etag := etagFor(info.ModTime(), page.offset, page.size, page.query)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+		if r.Header.Get("If-None-Match") == etag {
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
 		// Filter by IDs if provided
 		idsParam := r.URL.Query().Get("ids")
 		var filteredExhibits []ExhibitDTO
@@ -195,38 +241,95 @@ func main() {
 			filteredExhibits = exhibits
 		}
 
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		var matched []ExhibitDTO
+		for _, exhibit := range filteredExhibits {
+			if matchesExhibitQuery(exhibit, page.query) {
+				matched = append(matched, exhibit)
+			}
+		}
 
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(filteredExhibits); err != nil {
-			log.Printf("Error encoding response: %v", err)
+		sortExhibits(matched, page.sortBy, page.order)
+
+		count := len(matched)
+		start := page.offset
+		if start > count {
+			start = count
+		}
+		end := start + page.size
+		if end > count {
+			end = count
 		}
+		pageOfExhibits := matched[start:end]
+
+		next, previous := paginationLinks(r, count, page.offset, page.size)
+
+		w.Header().Set("ETag", etag)
+		writeJSON(w, Envelope{
+			Count:    count,
+			Next:     next,
+			Previous: previous,
+			Results:  pageOfExhibits,
+		})
 	})
 
 	http.HandleFunc("/artefacts", func(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Received request: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
 		filePath, err := findFile("qm_data.json")
 		if err != nil {
-			http.Error(w, "Error finding `qm_data.json`: " +err.Error(), http.StatusInternalServerError)
+			http.Error(w, "Error finding `qm_data.json`: "+err.Error(), http.StatusInternalServerError)
 			log.Printf("Error finding file: %v", err)
 			return
 		}
+		info, err := os.Stat(filePath)
+		if err != nil {
+			http.Error(w, "Error stating qm_data.json: "+err.Error(), http.StatusInternalServerError)
+			log.Printf("Error stating file %s: %v", filePath, err)
+			return
+		}
 		data, err := os.ReadFile(filePath)
 		if err != nil {
-			http.Error(w, "Error reading qm_data.json: " +err.Error(), http.StatusInternalServerError)
+			http.Error(w, "Error reading qm_data.json: "+err.Error(), http.StatusInternalServerError)
 			log.Printf("Error reading file %s: %v", filePath, err)
 			return
 		}
 
 		var qmResponse QMResponse
 		if err := json.Unmarshal(data, &qmResponse); err != nil {
-			http.Error(w, "Error parsing qm_data.json: "  +err.Error(), http.StatusInternalServerError)
+			http.Error(w, "Error parsing qm_data.json: "+err.Error(), http.StatusInternalServerError)
 			log.Printf("Error unmarshalling json: %v", err)
 			return
 		}
 
+		page := parsePageParams(r)
+
+		shouldFilterOpen, openAt, openLoc, err := openFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// open_now/open_on make the result set depend on wall-clock time, so
+		// the resolved instant (truncated to the minute) must be folded into
+		// the ETag alongside the negotiated representation — otherwise a
+		// client that correctly revalidates with If-None-Match would be
+		// served a stale 304 for an "open now" query made hours earlier.
+		openSignature := ""
+		if shouldFilterOpen {
+			openSignature = openAt.Truncate(time.Minute).Format(time.RFC3339) + "@" + openLoc.String()
+		}
+		etag := etagFor(info.ModTime(), page.offset, page.size, page.query, negotiatedFormat(r), openSignature)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+		if r.Header.Get("If-None-Match") == etag {
+			w.Header().Set("Vary", "Accept")
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
 		// Filter by objectNumbers if provided
 		objectNumbersParam := r.URL.Query().Get("objectNumbers")
 		var filteredArtefacts []ArtefactDTO
@@ -251,16 +354,53 @@ func main() {
 			filteredArtefacts = qmResponse.Results
 		}
 
+		var matched []ArtefactDTO
+		for _, artefact := range filteredArtefacts {
+			if !matchesArtefactQuery(artefact, page.query) {
+				continue
+			}
+			if shouldFilterOpen {
+				if open, _, _, _ := openStatusAt(artefact.OpeningTimes, openLoc, openAt); !open {
+					continue
+				}
+			}
+			matched = append(matched, artefact)
+		}
+
+		sortArtefacts(matched, page.sortBy, page.order)
+
+		count := len(matched)
+		start := page.offset
+		if start > count {
+			start = count
+		}
+		end := start + page.size
+		if end > count {
+			end = count
+		}
+		pageOfArtefacts := matched[start:end]
+
+		next, previous := paginationLinks(r, count, page.offset, page.size)
+
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(filteredArtefacts); err != nil {
-			log.Printf("Error encoding response: %v", err)
-		}
+		w.Header().Set("ETag", etag)
+		writeNegotiated(w, r, pageOfArtefacts, func() {
+			writeJSON(w, Envelope{
+				Count:    count,
+				Next:     next,
+				Previous: previous,
+				Results:  pageOfArtefacts,
+			})
+		})
 	})
 
+	http.HandleFunc("/exhibits/nearby", exhibitsNearbyHandler)
+	http.HandleFunc("/artefacts/nearby", artefactsNearbyHandler)
+	http.HandleFunc("/artefacts/", artefactDetailHandler)
+	http.HandleFunc("/exhibits/", exhibitDetailHandler)
+
 	log.Println("Starting server on :8080")
 	if err := http.ListenAndServe(":8080", nil); err != nil {
 		log.Fatal(err)
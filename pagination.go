@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultPageSize = 50
+const maxPageSize = 200
+
+// Envelope is the paginated response shape returned by /exhibits and
+// /artefacts, mirroring the upstream QMResponse envelope.
+type Envelope struct {
+	Count    int         `json:"count"`
+	Next     string      `json:"next"`
+	Previous string      `json:"previous"`
+	Results  interface{} `json:"results"`
+}
+
+// pageParams holds the parsed offset/size/sort/order/query parameters shared
+// by /exhibits and /artefacts.
+type pageParams struct {
+	offset int
+	size   int
+	sortBy string
+	order  string
+	query  string
+}
+
+func parsePageParams(r *http.Request) pageParams {
+	q := r.URL.Query()
+
+	params := pageParams{
+		offset: 0,
+		size:   defaultPageSize,
+		sortBy: "",
+		order:  "asc",
+		query:  strings.ToLower(strings.TrimSpace(q.Get("query"))),
+	}
+
+	if raw := q.Get("offset"); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v >= 0 {
+			params.offset = v
+		}
+	}
+
+	if raw := q.Get("size"); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v > 0 {
+			if v > maxPageSize {
+				v = maxPageSize
+			}
+			params.size = v
+		}
+	}
+
+	switch q.Get("sort") {
+	case "name", "artist", "id":
+		params.sortBy = q.Get("sort")
+	}
+
+	if q.Get("order") == "desc" {
+		params.order = "desc"
+	}
+
+	return params
+}
+
+// etagFor derives an ETag from the source file's mtime and the request's
+// paging/search parameters, so that two requests selecting the same page of
+// the same data produce the same tag. Extra components (e.g. the negotiated
+// representation, or a resolved open_now/open_on instant) can be appended so
+// that responses which vary on more than offset/size/query don't collide.
+func etagFor(mtime time.Time, offset, size int, query string, extra ...string) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%d:%d:%s", mtime.UnixNano(), offset, size, query)
+	for _, e := range extra {
+		fmt.Fprintf(h, ":%s", e)
+	}
+	return fmt.Sprintf(`"%x"`, h.Sum64())
+}
+
+// pageLink rewrites the request URL's offset query param, returning "" when
+// the resulting offset would be out of range.
+func pageLink(r *http.Request, offset int, count int) string {
+	if offset < 0 || offset >= count {
+		return ""
+	}
+	u := *r.URL
+	q := u.Query()
+	q.Set("offset", strconv.Itoa(offset))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func paginationLinks(r *http.Request, count, offset, size int) (next, previous string) {
+	if offset+size < count {
+		next = pageLink(r, offset+size, count)
+	}
+	if offset > 0 {
+		prevOffset := offset - size
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		previous = pageLink(r, prevOffset, count)
+	}
+	return
+}
+
+func containsFold(haystack, needle string) bool {
+	if needle == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(haystack), needle)
+}
+
+func matchesExhibitQuery(e ExhibitDTO, query string) bool {
+	if query == "" {
+		return true
+	}
+	if containsFold(e.Name.En, query) || containsFold(e.Name.Ar, query) ||
+		containsFold(e.ArtistName.En, query) || containsFold(e.ArtistName.Ar, query) ||
+		containsFold(e.BriefDescription.En, query) || containsFold(e.BriefDescription.Ar, query) {
+		return true
+	}
+	for _, tag := range e.Tags {
+		if containsFold(tag, query) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesArtefactQuery(a ArtefactDTO, query string) bool {
+	if query == "" {
+		return true
+	}
+	return containsFold(a.TitleEN, query) || containsFold(a.TitleAR, query) ||
+		containsFold(a.ObjectNameEN, query) || containsFold(a.ObjectNameAR, query) ||
+		containsFold(a.ArtistEN, query) || containsFold(a.ArtistAR, query) ||
+		containsFold(a.SummaryEN, query) || containsFold(a.SummaryAR, query)
+}
+
+func sortExhibits(exhibits []ExhibitDTO, sortBy, order string) {
+	if sortBy == "" {
+		return
+	}
+	less := func(i, j int) bool {
+		switch sortBy {
+		case "name":
+			return strings.ToLower(exhibits[i].Name.En) < strings.ToLower(exhibits[j].Name.En)
+		case "artist":
+			return strings.ToLower(exhibits[i].ArtistName.En) < strings.ToLower(exhibits[j].ArtistName.En)
+		default: // "id"
+			return exhibits[i].ID < exhibits[j].ID
+		}
+	}
+	if order == "desc" {
+		sort.SliceStable(exhibits, func(i, j int) bool { return less(j, i) })
+		return
+	}
+	sort.SliceStable(exhibits, less)
+}
+
+func sortArtefacts(artefacts []ArtefactDTO, sortBy, order string) {
+	if sortBy == "" {
+		return
+	}
+	less := func(i, j int) bool {
+		switch sortBy {
+		case "name":
+			return strings.ToLower(artefacts[i].TitleEN) < strings.ToLower(artefacts[j].TitleEN)
+		case "artist":
+			return strings.ToLower(artefacts[i].ArtistEN) < strings.ToLower(artefacts[j].ArtistEN)
+		default: // "id"
+			return artefacts[i].ObjectNumber < artefacts[j].ObjectNumber
+		}
+	}
+	if order == "desc" {
+		sort.SliceStable(artefacts, func(i, j int) bool { return less(j, i) })
+		return
+	}
+	sort.SliceStable(artefacts, less)
+}
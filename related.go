@@ -0,0 +1,354 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const defaultRelatedLimit = 10
+
+var stopwords = map[string]bool{
+	"the": true, "and": true, "of": true, "a": true, "an": true,
+	"in": true, "on": true, "at": true, "for": true, "to": true,
+	"with": true, "by": true, "from": true, "is": true,
+}
+
+// tokenize lowercases s, splits on runs of non-alphanumeric characters, and
+// drops short stopwords, producing a pseudo-tag set.
+func tokenize(s string) []string {
+	var tokens []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() == 0 {
+			return
+		}
+		word := cur.String()
+		cur.Reset()
+		if stopwords[word] {
+			return
+		}
+		tokens = append(tokens, word)
+	}
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			cur.WriteRune(r)
+		} else {
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+func toSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	intersection := 0
+	union := make(map[string]bool, len(a)+len(b))
+	for w := range a {
+		union[w] = true
+		if b[w] {
+			intersection++
+		}
+	}
+	for w := range b {
+		union[w] = true
+	}
+	if len(union) == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(len(union))
+}
+
+// scoreDist is a cached similarity score between two items, plus the
+// haversine distance used as a tiebreaker when both have coordinates.
+type scoreDist struct {
+	score       float64
+	distanceKm  float64
+	hasDistance bool
+}
+
+func rankRelated(row map[string]scoreDist, keys []string, limit int) []string {
+	sort.Slice(keys, func(i, j int) bool {
+		si, sj := row[keys[i]], row[keys[j]]
+		if si.score != sj.score {
+			return si.score > sj.score
+		}
+		if si.hasDistance != sj.hasDistance {
+			return si.hasDistance
+		}
+		if si.hasDistance && sj.hasDistance && si.distanceKm != sj.distanceKm {
+			return si.distanceKm < sj.distanceKm
+		}
+		return keys[i] < keys[j]
+	})
+	if limit > 0 && len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return keys
+}
+
+func parseRelatedLimit(r *http.Request) int {
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v > 0 {
+			return v
+		}
+	}
+	return defaultRelatedLimit
+}
+
+// exhibitRelatedCache holds the pairwise exhibit similarity matrix, rebuilt
+// whenever exhibits.json's mtime changes.
+type exhibitRelatedCache struct {
+	mu       sync.Mutex
+	mtime    time.Time
+	exhibits map[int]ExhibitDTO
+	matrix   map[int]map[string]scoreDist
+}
+
+var exhibitRelCache exhibitRelatedCache
+
+func (c *exhibitRelatedCache) get() (map[int]ExhibitDTO, map[int]map[string]scoreDist, error) {
+	filePath, err := findFile("exhibits.json")
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.matrix != nil && c.mtime.Equal(info.ModTime()) {
+		return c.exhibits, c.matrix, nil
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	var exhibits []ExhibitDTO
+	if err := json.Unmarshal(data, &exhibits); err != nil {
+		return nil, nil, err
+	}
+
+	tagSets := make([]map[string]bool, len(exhibits))
+	for i, e := range exhibits {
+		lowered := make([]string, len(e.Tags))
+		for j, tag := range e.Tags {
+			lowered[j] = strings.ToLower(tag)
+		}
+		tagSets[i] = toSet(lowered)
+	}
+
+	byID := make(map[int]ExhibitDTO, len(exhibits))
+	matrix := make(map[int]map[string]scoreDist, len(exhibits))
+	for i, a := range exhibits {
+		byID[a.ID] = a
+		row := make(map[string]scoreDist, len(exhibits)-1)
+		for j, b := range exhibits {
+			if i == j {
+				continue
+			}
+			score := 0.6 * jaccard(tagSets[i], tagSets[j])
+			if a.ArtistName.En != "" && strings.EqualFold(a.ArtistName.En, b.ArtistName.En) {
+				score += 0.3
+			}
+			if a.SiteName.En != "" && strings.EqualFold(a.SiteName.En, b.SiteName.En) {
+				score += 0.1
+			}
+			sd := scoreDist{score: score}
+			if !isZeroCoords(a.Coords) && !isZeroCoords(b.Coords) {
+				sd.distanceKm = haversineKm(a.Coords, b.Coords)
+				sd.hasDistance = true
+			}
+			row[strconv.Itoa(b.ID)] = sd
+		}
+		matrix[a.ID] = row
+	}
+
+	c.exhibits = byID
+	c.matrix = matrix
+	c.mtime = info.ModTime()
+	return byID, matrix, nil
+}
+
+type relatedExhibit struct {
+	ExhibitDTO
+	Score float64 `json:"score"`
+}
+
+func exhibitDetailHandler(w http.ResponseWriter, r *http.Request) {
+	segments := splitAndTrim(strings.TrimPrefix(r.URL.Path, "/exhibits/"), "/")
+	if len(segments) < 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	id, err := strconv.Atoi(segments[0])
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch segments[1] {
+	case "related":
+		exhibitRelatedHandler(w, r, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func exhibitRelatedHandler(w http.ResponseWriter, r *http.Request, id int) {
+	byID, matrix, err := exhibitRelCache.get()
+	if err != nil {
+		http.Error(w, "Error loading exhibits.json: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, ok := byID[id]; !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	row := matrix[id]
+	keys := make([]string, 0, len(row))
+	for k := range row {
+		keys = append(keys, k)
+	}
+	ranked := rankRelated(row, keys, parseRelatedLimit(r))
+
+	results := make([]relatedExhibit, 0, len(ranked))
+	for _, k := range ranked {
+		otherID, _ := strconv.Atoi(k)
+		results = append(results, relatedExhibit{ExhibitDTO: byID[otherID], Score: row[k].score})
+	}
+
+	writeJSON(w, results)
+}
+
+// artefactRelatedCache holds the pairwise artefact similarity matrix, rebuilt
+// whenever qm_data.json's mtime changes.
+type artefactRelatedCache struct {
+	mu        sync.Mutex
+	mtime     time.Time
+	artefacts map[string]ArtefactDTO
+	matrix    map[string]map[string]scoreDist
+}
+
+var artefactRelCache artefactRelatedCache
+
+func pseudoTags(a ArtefactDTO) map[string]bool {
+	var tokens []string
+	tokens = append(tokens, tokenize(a.ObjectNameEN)...)
+	tokens = append(tokens, tokenize(a.ArtistEN)...)
+	tokens = append(tokens, tokenize(a.Museum.Slug)...)
+	return toSet(tokens)
+}
+
+func (c *artefactRelatedCache) get() (map[string]ArtefactDTO, map[string]map[string]scoreDist, error) {
+	filePath, err := findFile("qm_data.json")
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.matrix != nil && c.mtime.Equal(info.ModTime()) {
+		return c.artefacts, c.matrix, nil
+	}
+
+	artefacts, err := loadArtefacts()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tagSets := make([]map[string]bool, len(artefacts))
+	for i, a := range artefacts {
+		tagSets[i] = pseudoTags(a)
+	}
+
+	byObjectNumber := make(map[string]ArtefactDTO, len(artefacts))
+	matrix := make(map[string]map[string]scoreDist, len(artefacts))
+	for i, a := range artefacts {
+		byObjectNumber[a.ObjectNumber] = a
+		row := make(map[string]scoreDist, len(artefacts)-1)
+		for j, b := range artefacts {
+			if i == j {
+				continue
+			}
+			score := 0.6 * jaccard(tagSets[i], tagSets[j])
+			if a.ArtistEN != "" && strings.EqualFold(a.ArtistEN, b.ArtistEN) {
+				score += 0.3
+			}
+			if a.Museum.Slug != "" && strings.EqualFold(a.Museum.Slug, b.Museum.Slug) {
+				score += 0.1
+			}
+			sd := scoreDist{score: score}
+			if !isZeroCoords(a.Coords) && !isZeroCoords(b.Coords) {
+				sd.distanceKm = haversineKm(a.Coords, b.Coords)
+				sd.hasDistance = true
+			}
+			row[b.ObjectNumber] = sd
+		}
+		matrix[a.ObjectNumber] = row
+	}
+
+	c.artefacts = byObjectNumber
+	c.matrix = matrix
+	c.mtime = info.ModTime()
+	return byObjectNumber, matrix, nil
+}
+
+type relatedArtefact struct {
+	ArtefactDTO
+	Score float64 `json:"score"`
+}
+
+func artefactRelatedHandler(w http.ResponseWriter, r *http.Request, objectNumber string) {
+	byObjectNumber, matrix, err := artefactRelCache.get()
+	if err != nil {
+		http.Error(w, "Error loading qm_data.json: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, ok := byObjectNumber[objectNumber]; !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	row := matrix[objectNumber]
+	keys := make([]string, 0, len(row))
+	for k := range row {
+		keys = append(keys, k)
+	}
+	ranked := rankRelated(row, keys, parseRelatedLimit(r))
+
+	results := make([]relatedArtefact, 0, len(ranked))
+	for _, k := range ranked {
+		results = append(results, relatedArtefact{ArtefactDTO: byObjectNumber[k], Score: row[k].score})
+	}
+
+	writeJSON(w, results)
+}